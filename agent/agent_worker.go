@@ -176,7 +176,12 @@ func (a *AgentWorker) Start(ctx context.Context, idleMonitor *IdleMonitor) error
 		// measure.
 		idleMonitor.MarkBusy(a.agent.UUID)
 
-		return a.AcquireAndRunJob(ctx, a.agentConfiguration.AcquireJob)
+		if err := a.AcquireAndRunJob(ctx, a.agentConfiguration.AcquireJob); err != nil {
+			a.jobLogger(&api.Job{ID: a.agentConfiguration.AcquireJob}).Error("%v", err)
+			return err
+		}
+
+		return nil
 	}
 
 	return a.startPingLoop(ctx, idleMonitor)
@@ -204,10 +209,10 @@ func (a *AgentWorker) startPingLoop(ctx context.Context, idleMonitor *IdleMonito
 
 				// Runs the job, only errors if something goes wrong
 				if runErr := a.AcceptAndRunJob(ctx, job); runErr != nil {
-					a.logger.Error("%v", runErr)
+					a.jobLogger(job).Error("%v", runErr)
 				} else {
 					if a.agentConfiguration.DisconnectAfterJob {
-						a.logger.Info("Job finished. Disconnecting...")
+						a.jobLogger(job).Info("Job finished. Disconnecting...")
 						return nil
 					}
 					lastActionTime = time.Now()
@@ -279,16 +284,16 @@ func (a *AgentWorker) Stop(graceful bool) {
 			}
 		}
 	} else {
-		// If there's a job running, kill it, then disconnect
-		if a.jobRunner != nil {
+		// If there's a job running, kill it, then disconnect. RunJob
+		// clears jobRunner when the job finishes, so only read it once.
+		if jr := a.jobRunner; jr != nil {
 			a.logger.Info("Forcefully stopping agent. The current job will be canceled before disconnecting...")
 
 			// Kill the current job. Doesn't do anything if the job
 			// is already being killed, so it's safe to call
 			// multiple times.
-			err := a.jobRunner.CancelAndStop()
-			if err != nil {
-				a.logger.Error("Unexpected error canceling job (err: %s)", err)
+			if err := jr.CancelAndStop(); err != nil {
+				jr.logger.Error("Unexpected error canceling job (err: %s)", err)
 			}
 		} else {
 			a.logger.Info("Forcefully stopping agent. Since there is no job running, the agent will disconnect immediately")
@@ -421,7 +426,9 @@ func (a *AgentWorker) Ping() (*api.Job, error) {
 // Attempts to acquire a job and run it, only returns an error if something
 // goes wrong
 func (a *AgentWorker) AcquireAndRunJob(ctx context.Context, jobId string) error {
-	a.logger.Info("Attempting to acquire job %s...", jobId)
+	l := a.jobLogger(&api.Job{ID: jobId})
+
+	l.Info("Attempting to acquire job %s...", jobId)
 
 	// Acquire the job using the ID we were provided. We'll retry as best
 	// we can on non 422 error.
@@ -445,10 +452,10 @@ func (a *AgentWorker) AcquireAndRunJob(ctx context.Context, jobId string) error
 			// succesfully *tried* to acquire the job, but
 			// Buildkite rejected the finish for some reason.
 			if response != nil && response.StatusCode == 422 {
-				a.logger.Warn("Buildkite rejected the call to acquire the job (%s)", err)
+				l.Warn("Buildkite rejected the call to acquire the job (%s)", err)
 				r.Break()
 			} else {
-				a.logger.Warn("%s (%s)", err, r)
+				l.Warn("%s (%s)", err, r)
 			}
 		}
 
@@ -466,7 +473,9 @@ func (a *AgentWorker) AcquireAndRunJob(ctx context.Context, jobId string) error
 
 // Accepts a job and runs it, only returns an error if something goes wrong
 func (a *AgentWorker) AcceptAndRunJob(ctx context.Context, job *api.Job) error {
-	a.logger.Info("Assigned job %s. Accepting...", job.ID)
+	l := a.jobLogger(job)
+
+	l.Info("Assigned job %s. Accepting...", job.ID)
 
 	// Accept the job. We'll retry on connection related issues, but if
 	// Buildkite returns a 422 or 500 for example, we'll just bail out,
@@ -480,9 +489,9 @@ func (a *AgentWorker) AcceptAndRunJob(ctx context.Context, job *api.Job) error {
 		accepted, _, err = a.apiClient.AcceptJob(job)
 		if err != nil {
 			if api.IsRetryableError(err) {
-				a.logger.Warn("%s (%s)", err, r)
+				l.Warn("%s (%s)", err, r)
 			} else {
-				a.logger.Warn("Buildkite rejected the call to accept the job (%s)", err)
+				l.Warn("Buildkite rejected the call to accept the job (%s)", err)
 				r.Break()
 			}
 		}
@@ -509,7 +518,7 @@ func (a *AgentWorker) RunJob(ctx context.Context, acceptResponse *api.Job) error
 	})
 
 	// Now that we've got a job to do, we can start it.
-	jr, err := NewJobRunner(a.logger, jobMetricsScope, a.agent, acceptResponse, a.apiClient, JobRunnerConfig{
+	jr, err := NewJobRunner(a.jobLogger(acceptResponse), jobMetricsScope, a.agent, acceptResponse, a.apiClient, JobRunnerConfig{
 		Debug:              a.debug,
 		DebugHTTP:          a.debugHTTP,
 		CancelSignal:       a.cancelSig,
@@ -520,10 +529,15 @@ func (a *AgentWorker) RunJob(ctx context.Context, acceptResponse *api.Job) error
 	if err != nil {
 		return fmt.Errorf("Failed to initialize job: %v", err)
 	}
+	// Stop reads the job runner under the same lock
+	a.stopMutex.Lock()
 	a.jobRunner = jr
+	a.stopMutex.Unlock()
 	defer func() {
 		// No more job, no more runner.
+		a.stopMutex.Lock()
 		a.jobRunner = nil
+		a.stopMutex.Unlock()
 	}()
 
 	// Start running the job
@@ -534,6 +548,19 @@ func (a *AgentWorker) RunJob(ctx context.Context, acceptResponse *api.Job) error
 	return nil
 }
 
+// jobLogger returns the worker's logger with fields identifying the job, so
+// output from concurrent jobs can be told apart. The build URL is only known
+// once the job has been accepted or acquired.
+func (a *AgentWorker) jobLogger(job *api.Job) logger.Logger {
+	fields := []logger.Field{logger.StringField(`job`, job.ID)}
+
+	if buildURL := job.Env[`BUILDKITE_BUILD_URL`]; buildURL != "" {
+		fields = append(fields, logger.StringField(`build_url`, buildURL))
+	}
+
+	return a.logger.WithFields(fields...)
+}
+
 // Disconnect notifies the Buildkite API that this agent worker/session is
 // permanently disconnecting. Don't spend long retrying, because we want to
 // disconnect as fast as possible.
@@ -19,11 +19,13 @@ type APIClient interface {
 	ExistsMetaData(string, string) (*api.MetaDataExists, *api.Response, error)
 	FinishJob(*api.Job) (*api.Response, error)
 	FromAgentRegisterResponse(*api.AgentRegisterResponse) *api.Client
+	FromJobToken(string) *api.Client
 	FromPing(*api.Ping) *api.Client
 	GetJobState(string) (*api.JobState, *api.Response, error)
 	GetMetaData(string, string) (*api.MetaData, *api.Response, error)
 	Heartbeat() (*api.Heartbeat, *api.Response, error)
 	MetaDataKeys(string) ([]string, *api.Response, error)
+	OIDCToken(*api.OIDCTokenRequest) (*api.OIDCToken, *api.Response, error)
 	Ping() (*api.Ping, *api.Response, error)
 	Register(*api.AgentRegisterRequest) (*api.AgentRegisterResponse, *api.Response, error)
 	SaveHeaderTimes(string, *api.HeaderTimes) (*api.Response, error)
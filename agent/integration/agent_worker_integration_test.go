@@ -0,0 +1,204 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/buildkite/agent/v3/agent"
+	"github.com/buildkite/agent/v3/api"
+	"github.com/buildkite/agent/v3/logger"
+	"github.com/buildkite/agent/v3/metrics"
+	"github.com/buildkite/bintest/v3"
+)
+
+func TestAgentWorkerTagsJobLogLinesWithJob(t *testing.T) {
+	ag := &api.AgentRegisterResponse{
+		UUID:              "my-agent-id",
+		AccessToken:       "llamasrock",
+		PingInterval:      1,
+		HeartbeatInterval: 60,
+	}
+
+	j := &api.Job{
+		ID:                 "my-job-id",
+		ChunksMaxSizeBytes: 1024,
+		Env: map[string]string{
+			"BUILDKITE_COMMAND":   "echo hello world",
+			"BUILDKITE_BUILD_URL": "https://buildkite.com/llamas/builds/1",
+		},
+	}
+
+	server := createTestAgentEndpoint(t, j)
+	defer server.Close()
+
+	bs := mockBootstrap(t, func(c *bintest.Call) {
+		c.Exit(0)
+	})
+	defer bs.CheckAndClose(t)
+
+	cfg := agent.AgentConfiguration{
+		BootstrapScript:    bs.Path,
+		DisconnectAfterJob: true,
+	}
+
+	out := &logBuffer{}
+	worker := newTestAgentWorker(server.URL, ag, cfg, out)
+
+	if err := worker.Start(context.Background(), agent.NewIdleMonitor(1)); err != nil {
+		t.Fatalf("worker.Start() error = %v", err)
+	}
+
+	// Lines from the worker itself, before the job was accepted
+	for _, msg := range []string{
+		"Assigned job my-job-id. Accepting...",
+		"Job finished. Disconnecting...",
+	} {
+		line := out.find(t, msg)
+		if got, want := line["job"], j.ID; got != want {
+			t.Errorf("%q job = %q, want %q", msg, got, want)
+		}
+	}
+
+	// Lines from the job runner, which also know the build
+	line := out.find(t, "Starting job my-job-id")
+	if got, want := line["job"], j.ID; got != want {
+		t.Errorf("%q job = %q, want %q", "Starting job my-job-id", got, want)
+	}
+	if got, want := line["build_url"], j.Env["BUILDKITE_BUILD_URL"]; got != want {
+		t.Errorf("%q build_url = %q, want %q", "Starting job my-job-id", got, want)
+	}
+}
+
+func TestAgentWorkerForcedStopTagsCancelLinesWithJob(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("bootstrap is a shell script")
+	}
+
+	ag := &api.AgentRegisterResponse{
+		UUID:              "my-agent-id",
+		AccessToken:       "llamasrock",
+		PingInterval:      1,
+		HeartbeatInterval: 60,
+	}
+
+	j := &api.Job{
+		ID:                 "my-job-id",
+		ChunksMaxSizeBytes: 1024,
+	}
+
+	server := createTestAgentEndpoint(t, j)
+	defer server.Close()
+
+	// A bootstrap that runs until it's interrupted, and lets us know when
+	// it has started
+	dir := t.TempDir()
+	started := filepath.Join(dir, "started")
+	bootstrap := filepath.Join(dir, "bootstrap")
+	script := "#!/bin/sh\ntouch '" + started + "'\nsleep 30\n"
+	if err := os.WriteFile(bootstrap, []byte(script), 0755); err != nil {
+		t.Fatalf("os.WriteFile(%q) error = %v", bootstrap, err)
+	}
+
+	cfg := agent.AgentConfiguration{
+		BootstrapScript:   bootstrap,
+		AcquireJob:        j.ID,
+		CancelGracePeriod: 5,
+	}
+
+	out := &logBuffer{}
+	worker := newTestAgentWorker(server.URL, ag, cfg, out)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- worker.Start(context.Background(), agent.NewIdleMonitor(1))
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := os.Stat(started); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("bootstrap didn't start within 10s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	worker.Stop(false)
+
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Fatalf("worker.Start() error = %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("worker.Start() didn't return within 10s of worker.Stop(false)")
+	}
+
+	line := out.find(t, "Canceling job my-job-id")
+	if got, want := line["job"], j.ID; got != want {
+		t.Errorf("%q job = %q, want %q", "Canceling job my-job-id", got, want)
+	}
+}
+
+// Each started worker registers a health check handler for its spawn index,
+// and registering one twice panics, so every test worker gets its own
+var lastSpawnIndex int32
+
+// newTestAgentWorker returns a worker talking to the mock agent API, logging
+// JSON lines to w
+func newTestAgentWorker(endpoint string, ag *api.AgentRegisterResponse, cfg agent.AgentConfiguration, w *logBuffer) *agent.AgentWorker {
+	l := logger.NewConsoleLogger(logger.NewJSONPrinter(w), func(int) {})
+
+	client := api.NewClient(logger.Discard, api.Config{
+		Endpoint: endpoint,
+		Token:    "llamas",
+	})
+
+	return agent.NewAgentWorker(l, ag, metrics.NewCollector(logger.Discard, metrics.CollectorConfig{}), client, agent.AgentWorkerConfig{
+		SpawnIndex:         int(atomic.AddInt32(&lastSpawnIndex, 1)),
+		AgentConfiguration: cfg,
+	})
+}
+
+// logBuffer collects JSON log lines written from many goroutines
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// find returns the fields of the first line whose message starts with msg
+func (b *logBuffer) find(t *testing.T, msg string) map[string]string {
+	t.Helper()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, raw := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var line map[string]string
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			t.Fatalf("json.Unmarshal(%q) error = %v", raw, err)
+		}
+		if strings.HasPrefix(line["msg"], msg) {
+			return line
+		}
+	}
+
+	t.Fatalf("no log line starting with %q in:\n%s", msg, b.buf.String())
+	return nil
+}
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -93,19 +94,13 @@ func TestJobRunnerIgnoresPipelineChangesToProtectedVars(t *testing.T) {
 
 func runJob(t *testing.T, ag *api.AgentRegisterResponse, j *api.Job, cfg agent.AgentConfiguration, bootstrap func(c *bintest.Call)) {
 	// create a mock agent API
-	server := createTestAgentEndpoint(t, `my-job-id`)
+	server := createTestAgentEndpoint(t, j)
 	defer server.Close()
 
 	// set up a mock bootstrap that the runner will call
-	bs, err := bintest.NewMock("buildkite-agent-bootstrap")
-	if err != nil {
-		t.Fatalf("bintest.NewMock() error = %v", err)
-	}
+	bs := mockBootstrap(t, bootstrap)
 	defer bs.CheckAndClose(t)
 
-	// execute the callback we have inside the bootstrap mock
-	bs.Expect().Once().AndExitWith(0).AndCallFunc(bootstrap)
-
 	l := logger.Discard
 
 	// minimal metrics, this could be cleaner
@@ -132,9 +127,32 @@ func runJob(t *testing.T, ag *api.AgentRegisterResponse, j *api.Job, cfg agent.A
 	}
 }
 
-func createTestAgentEndpoint(t *testing.T, jobID string) *httptest.Server {
+// mockBootstrap returns a mock bootstrap that runs the provided callback once
+func mockBootstrap(t *testing.T, bootstrap func(c *bintest.Call)) *bintest.Mock {
+	bs, err := bintest.NewMock("buildkite-agent-bootstrap")
+	if err != nil {
+		t.Fatalf("bintest.NewMock() error = %v", err)
+	}
+
+	// execute the callback we have inside the bootstrap mock
+	bs.Expect().Once().AndExitWith(0).AndCallFunc(bootstrap)
+
+	return bs
+}
+
+// createTestAgentEndpoint returns a mock agent API that assigns the provided
+// job on ping, and accepts all the calls made while running it
+func createTestAgentEndpoint(t *testing.T, j *api.Job) *httptest.Server {
+	jobID := j.ID
+
 	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		switch req.URL.Path {
+		case `/ping`:
+			rw.WriteHeader(http.StatusOK)
+			json.NewEncoder(rw).Encode(&api.Ping{Job: j})
+		case `/jobs/` + jobID + `/accept`, `/jobs/` + jobID + `/acquire`:
+			rw.WriteHeader(http.StatusOK)
+			json.NewEncoder(rw).Encode(j)
 		case `/jobs/` + jobID:
 			rw.WriteHeader(http.StatusOK)
 			fmt.Fprintf(rw, `{"state":"running"}`)
@@ -113,9 +113,7 @@ func NewJobRunner(l logger.Logger, scope *metrics.Scope, ag *api.AgentRegisterRe
 	// If the accept response has a token attached, we should use that instead of the Agent Access Token that
 	// our current apiClient is using
 	if job.Token != "" {
-		clientConf := apiClient.Config()
-		clientConf.Token = job.Token
-		runner.apiClient = api.NewClient(l, clientConf)
+		runner.apiClient = apiClient.FromJobToken(job.Token)
 	}
 
 	// Create our header times struct
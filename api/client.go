@@ -22,8 +22,12 @@ import (
 )
 
 const (
-	defaultEndpoint  = "https://agent.buildkite.com/"
-	defaultUserAgent = "buildkite-agent/api"
+	defaultEndpoint        = "https://agent.buildkite.com/"
+	defaultUserAgent       = "buildkite-agent/api"
+	defaultTimeout         = 60 * time.Second
+	defaultKeepAlive       = 30 * time.Second
+	defaultMaxIdleConns    = 100
+	defaultIdleConnTimeout = 90 * time.Second
 )
 
 // Config is configuration for the API Client
@@ -41,6 +45,22 @@ type Config struct {
 	// If true, only HTTP2 is disabled
 	DisableHTTP2 bool
 
+	// How long a request may take, including reading the response, before
+	// it is abandoned. Defaults to 60 seconds.
+	Timeout time.Duration
+
+	// The interval between TCP keep-alive probes on API connections.
+	// Defaults to 30 seconds.
+	KeepAlive time.Duration
+
+	// The maximum number of idle connections kept open for reuse.
+	// Defaults to 100.
+	MaxIdleConns int
+
+	// How long an idle connection is kept open before it is closed.
+	// Defaults to 90 seconds.
+	IdleConnTimeout time.Duration
+
 	// If true, requests and responses will be dumped and set to the logger
 	DebugHTTP bool
 
@@ -56,12 +76,23 @@ type Client struct {
 	// HTTP client used to communicate with the API.
 	client *http.Client
 
+	// The underlying transport, shared with any clients derived from this
+	// one so they reuse the same connection pool. Nil if a custom
+	// HTTPClient was provided.
+	transport http.RoundTripper
+
 	// The logger used
 	logger logger.Logger
 }
 
 // NewClient returns a new Buildkite Agent API Client.
 func NewClient(l logger.Logger, conf Config) *Client {
+	return newClient(l, conf, nil)
+}
+
+// newClient creates a Client that sends requests through the provided
+// transport, or a newly created one if it's nil
+func newClient(l logger.Logger, conf Config, transport http.RoundTripper) *Client {
 	if conf.Endpoint == "" {
 		conf.Endpoint = defaultEndpoint
 	}
@@ -70,39 +101,69 @@ func NewClient(l logger.Logger, conf Config) *Client {
 		conf.UserAgent = defaultUserAgent
 	}
 
+	if conf.Timeout == 0 {
+		conf.Timeout = defaultTimeout
+	}
+
+	if conf.KeepAlive == 0 {
+		conf.KeepAlive = defaultKeepAlive
+	}
+
+	if conf.MaxIdleConns == 0 {
+		conf.MaxIdleConns = defaultMaxIdleConns
+	}
+
+	if conf.IdleConnTimeout == 0 {
+		conf.IdleConnTimeout = defaultIdleConnTimeout
+	}
+
 	httpClient := conf.HTTPClient
 	if conf.HTTPClient == nil {
-		t := &http.Transport{
-			Proxy:              http.ProxyFromEnvironment,
-			DisableCompression: false,
-			DisableKeepAlives:  false,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-			}).DialContext,
-			MaxIdleConns:        100,
-			IdleConnTimeout:     90 * time.Second,
-			TLSHandshakeTimeout: 30 * time.Second,
-		}
-
-		if conf.DisableHTTP2 {
-			t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		if transport == nil {
+			transport = newTransport(conf)
 		}
 
 		httpClient = &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout: conf.Timeout,
 			Transport: &authenticatedTransport{
 				Token:    conf.Token,
-				Delegate: t,
+				Delegate: transport,
 			},
 		}
 	}
 
 	return &Client{
-		logger: l,
-		client: httpClient,
-		conf:   conf,
+		logger:    l,
+		client:    httpClient,
+		transport: transport,
+		conf:      conf,
+	}
+}
+
+// newTransport returns the transport used for API requests when no custom
+// HTTPClient is configured
+func newTransport(conf Config) *http.Transport {
+	t := &http.Transport{
+		Proxy:              http.ProxyFromEnvironment,
+		DisableCompression: false,
+		DisableKeepAlives:  false,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: conf.KeepAlive,
+		}).DialContext,
+		MaxIdleConns: conf.MaxIdleConns,
+		// Nearly every request goes to the one API host, so allow that host
+		// to hold the whole idle pool instead of the default of 2
+		MaxIdleConnsPerHost: conf.MaxIdleConns,
+		IdleConnTimeout:     conf.IdleConnTimeout,
+		TLSHandshakeTimeout: 30 * time.Second,
+	}
+
+	if conf.DisableHTTP2 {
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
 	}
+
+	return t
 }
 
 // Config returns the internal configuration for the Client
@@ -123,7 +184,7 @@ func (c *Client) FromAgentRegisterResponse(resp *AgentRegisterResponse) *Client
 		conf.Endpoint = resp.Endpoint
 	}
 
-	return NewClient(c.logger, conf)
+	return newClient(c.logger, conf, c.transport)
 }
 
 // FromPing returns a new instance using a new endpoint from a ping response
@@ -135,7 +196,18 @@ func (c *Client) FromPing(resp *Ping) *Client {
 		conf.Endpoint = resp.Endpoint
 	}
 
-	return NewClient(c.logger, conf)
+	return newClient(c.logger, conf, c.transport)
+}
+
+// FromJobToken returns a new instance that authenticates with a job's token
+// instead of the agent access token
+func (c *Client) FromJobToken(token string) *Client {
+	conf := c.conf
+
+	// Override the agent access token with the job token
+	conf.Token = token
+
+	return newClient(c.logger, conf, c.transport)
 }
 
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
@@ -2,10 +2,13 @@ package api_test
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/buildkite/agent/v3/api"
 	"github.com/buildkite/agent/v3/logger"
@@ -65,6 +68,124 @@ func TestRegisteringAndConnectingClient(t *testing.T) {
 	}
 }
 
+func TestDerivedClientsShareConnections(t *testing.T) {
+	var mu sync.Mutex
+	var conns int
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/register":
+			rw.WriteHeader(http.StatusOK)
+			fmt.Fprint(rw, `{"id":"12-34-56-78-91", "name":"agent-1", "access_token":"alpacas"}`)
+
+		case "/connect":
+			rw.WriteHeader(http.StatusOK)
+			fmt.Fprint(rw, `{}`)
+
+		case "/jobs/1/start":
+			if got, want := authToken(req), "job-token"; got != want {
+				http.Error(rw, fmt.Sprintf("authToken(req) = %q, want %q", got, want), http.StatusUnauthorized)
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+			fmt.Fprint(rw, `{}`)
+
+		default:
+			http.Error(rw, fmt.Sprintf("not found; method = %q, path = %q", req.Method, req.URL.Path), http.StatusNotFound)
+		}
+	}))
+	server.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	c := api.NewClient(logger.Discard, api.Config{
+		Endpoint: server.URL,
+		Token:    "llamas",
+	})
+
+	// Register a couple of agents, as --spawn does, connect each of them and
+	// start a job using the job's own token
+	for i := 0; i < 2; i++ {
+		regResp, _, err := c.Register(&api.AgentRegisterRequest{})
+		if err != nil {
+			t.Fatalf("c.Register(&AgentRegisterRequest{}) error = %v", err)
+		}
+
+		c2 := c.FromAgentRegisterResponse(regResp)
+		if _, err := c2.Connect(); err != nil {
+			t.Fatalf("c.FromAgentRegisterResponse(regResp).Connect() error = %v", err)
+		}
+
+		if _, err := c2.FromJobToken("job-token").StartJob(&api.Job{ID: "1"}); err != nil {
+			t.Fatalf("c2.FromJobToken(%q).StartJob(&Job{ID: %q}) error = %v", "job-token", "1", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if got, want := conns, 1; got != want {
+		t.Errorf("server saw %d connections, want %d", got, want)
+	}
+}
+
+func TestClientConfigDefaults(t *testing.T) {
+	conf := api.NewClient(logger.Discard, api.Config{Token: "llamas"}).Config()
+
+	if got, want := conf.Timeout, 60*time.Second; got != want {
+		t.Errorf("conf.Timeout = %v, want %v", got, want)
+	}
+
+	if got, want := conf.KeepAlive, 30*time.Second; got != want {
+		t.Errorf("conf.KeepAlive = %v, want %v", got, want)
+	}
+
+	if got, want := conf.MaxIdleConns, 100; got != want {
+		t.Errorf("conf.MaxIdleConns = %d, want %d", got, want)
+	}
+
+	if got, want := conf.IdleConnTimeout, 90*time.Second; got != want {
+		t.Errorf("conf.IdleConnTimeout = %v, want %v", got, want)
+	}
+}
+
+func TestClientTimeoutAbandonsSlowRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-req.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client := api.NewClient(logger.Discard, api.Config{
+		Endpoint: server.URL,
+		Token:    "llamas",
+		Timeout:  50 * time.Millisecond,
+	})
+
+	// Clients derived for a job get their own http.Client, which should keep
+	// the configured timeout
+	for name, c := range map[string]*api.Client{
+		"agent": client,
+		"job":   client.FromJobToken("job-token"),
+	} {
+		start := time.Now()
+		if _, _, err := c.Ping(); err == nil {
+			t.Errorf("%s client.Ping() error = nil, want a timeout", name)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%s client.Ping() took %v, want it abandoned after 50ms", name, elapsed)
+		}
+	}
+}
+
 func authToken(req *http.Request) string {
 	return strings.TrimPrefix(req.Header.Get("Authorization"), "Token ")
 }
@@ -120,6 +120,12 @@ type AgentStartConfig struct {
 	Endpoint  string `cli:"endpoint" validate:"required"`
 	NoHTTP2   bool   `cli:"no-http2"`
 
+	// API connection tuning
+	HTTPTimeout         int `cli:"http-timeout"`
+	HTTPKeepAlive       int `cli:"http-keepalive"`
+	HTTPMaxIdleConns    int `cli:"http-max-idle-conns"`
+	HTTPIdleConnTimeout int `cli:"http-idle-conn-timeout"`
+
 	// Deprecated
 	NoSSHFingerprintVerification bool     `cli:"no-automatic-ssh-fingerprint-verification" deprecated-and-renamed-to:"NoSSHKeyscan"`
 	MetaData                     []string `cli:"meta-data" deprecated-and-renamed-to:"Tags"`
@@ -516,6 +522,30 @@ var AgentStartCommand = cli.Command{
 		EndpointFlag,
 		NoHTTP2Flag,
 		DebugHTTPFlag,
+		cli.IntFlag{
+			Name:   "http-timeout",
+			Value:  60,
+			Usage:  "The number of seconds a request to the Agent API may take before it is abandoned. Must be greater than 0",
+			EnvVar: "BUILDKITE_AGENT_HTTP_TIMEOUT",
+		},
+		cli.IntFlag{
+			Name:   "http-keepalive",
+			Value:  30,
+			Usage:  "The number of seconds between TCP keep-alive probes on connections to the Agent API. Must be greater than 0",
+			EnvVar: "BUILDKITE_AGENT_HTTP_KEEPALIVE",
+		},
+		cli.IntFlag{
+			Name:   "http-max-idle-conns",
+			Value:  100,
+			Usage:  "The maximum number of idle connections to the Agent API kept open for reuse. Must be greater than 0",
+			EnvVar: "BUILDKITE_AGENT_HTTP_MAX_IDLE_CONNS",
+		},
+		cli.IntFlag{
+			Name:   "http-idle-conn-timeout",
+			Value:  90,
+			Usage:  "The number of seconds an idle connection to the Agent API is kept open before it is closed. Must be greater than 0",
+			EnvVar: "BUILDKITE_AGENT_HTTP_IDLE_CONN_TIMEOUT",
+		},
 
 		// Global flags
 		NoColorFlag,
@@ -810,7 +840,11 @@ var AgentStartCommand = cli.Command{
 		}
 
 		// Create the API client
-		client := api.NewClient(l, loadAPIClientConfig(cfg, `Token`))
+		apiClientConf := loadAPIClientConfig(cfg, `Token`)
+		if err := setAPIConnectionTuning(&apiClientConf, cfg); err != nil {
+			l.Fatal("%s", err)
+		}
+		client := api.NewClient(l, apiClientConf)
 
 		// The registration request for all agents
 		registerReq := api.AgentRegisterRequest{
@@ -917,6 +951,32 @@ var AgentStartCommand = cli.Command{
 	},
 }
 
+// setAPIConnectionTuning copies the connection tuning flags into conf. The API
+// client treats zero values as "use the default", so anything that isn't
+// positive is rejected rather than silently replaced.
+func setAPIConnectionTuning(conf *api.Config, cfg AgentStartConfig) error {
+	for _, flag := range []struct {
+		name  string
+		value int
+	}{
+		{"http-timeout", cfg.HTTPTimeout},
+		{"http-keepalive", cfg.HTTPKeepAlive},
+		{"http-max-idle-conns", cfg.HTTPMaxIdleConns},
+		{"http-idle-conn-timeout", cfg.HTTPIdleConnTimeout},
+	} {
+		if flag.value <= 0 {
+			return fmt.Errorf("%s must be greater than 0, got %d", flag.name, flag.value)
+		}
+	}
+
+	conf.Timeout = time.Duration(cfg.HTTPTimeout) * time.Second
+	conf.KeepAlive = time.Duration(cfg.HTTPKeepAlive) * time.Second
+	conf.MaxIdleConns = cfg.HTTPMaxIdleConns
+	conf.IdleConnTimeout = time.Duration(cfg.HTTPIdleConnTimeout) * time.Second
+
+	return nil
+}
+
 func handlePoolSignals(l logger.Logger, pool *agent.AgentPool) chan os.Signal {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt,
@@ -5,7 +5,9 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/buildkite/agent/v3/api"
 	"github.com/buildkite/agent/v3/logger"
 	"github.com/stretchr/testify/assert"
 )
@@ -71,3 +73,47 @@ func TestAgentShutdownHook(t *testing.T) {
 		assert.Equal(t, []string{}, log.Messages)
 	})
 }
+
+func TestSetAPIConnectionTuning(t *testing.T) {
+	var conf api.Config
+	err := setAPIConnectionTuning(&conf, AgentStartConfig{
+		HTTPTimeout:         120,
+		HTTPKeepAlive:       15,
+		HTTPMaxIdleConns:    500,
+		HTTPIdleConnTimeout: 30,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 120*time.Second, conf.Timeout)
+	assert.Equal(t, 15*time.Second, conf.KeepAlive)
+	assert.Equal(t, 500, conf.MaxIdleConns)
+	assert.Equal(t, 30*time.Second, conf.IdleConnTimeout)
+}
+
+func TestSetAPIConnectionTuningRejectsNonPositiveValues(t *testing.T) {
+	valid := AgentStartConfig{
+		HTTPTimeout:         60,
+		HTTPKeepAlive:       30,
+		HTTPMaxIdleConns:    100,
+		HTTPIdleConnTimeout: 90,
+	}
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*AgentStartConfig)
+		err    string
+	}{
+		{"zero timeout", func(c *AgentStartConfig) { c.HTTPTimeout = 0 }, "http-timeout must be greater than 0, got 0"},
+		{"negative keepalive", func(c *AgentStartConfig) { c.HTTPKeepAlive = -1 }, "http-keepalive must be greater than 0, got -1"},
+		{"negative max idle conns", func(c *AgentStartConfig) { c.HTTPMaxIdleConns = -1 }, "http-max-idle-conns must be greater than 0, got -1"},
+		{"zero idle conn timeout", func(c *AgentStartConfig) { c.HTTPIdleConnTimeout = 0 }, "http-idle-conn-timeout must be greater than 0, got 0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := valid
+			tc.modify(&cfg)
+
+			var conf api.Config
+			assert.EqualError(t, setAPIConnectionTuning(&conf, cfg), tc.err)
+		})
+	}
+}
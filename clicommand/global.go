@@ -117,6 +117,11 @@ func CreateLogger(cfg interface{}) logger.Logger {
 			}
 		}
 
+		// Job build URLs are long, and only useful to log aggregators
+		printer.IsVisibleFn = func(field logger.Field) bool {
+			return field.Key() != "build_url"
+		}
+
 		// Turn off color if a NoColor option is present
 		noColor, err := reflections.GetField(cfg, "NoColor")
 		if noColor == true && err == nil {